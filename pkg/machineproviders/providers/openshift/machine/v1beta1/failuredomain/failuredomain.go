@@ -0,0 +1,688 @@
+/*
+Copyright 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package failuredomain provides a common representation of a failure domain
+// that can be constructed either from a ControlPlaneMachineSet's failure
+// domain configuration, or reverse engineered from a set of existing
+// Machines. This allows the reconciler to reason about failure domains
+// without needing to know which platform it is running on.
+package failuredomain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+)
+
+var (
+	// errMissingFailureDomainConfiguration is returned when the failure domain
+	// configuration for the given platform is not present.
+	errMissingFailureDomainConfiguration = errors.New("missing failure domain configuration")
+
+	// errMachineMissingProviderSpec is returned when a Machine does not have a
+	// provider spec set, so no failure domain can be determined from it.
+	errMachineMissingProviderSpec = errors.New("missing provider spec")
+)
+
+// FailureDomain is a common interface that each of the platform specific
+// failure domains adhere to. It allows external code to construct and
+// compare failure domains without needing to know the details of the
+// underlying platform representation.
+type FailureDomain interface {
+	fmt.Stringer
+
+	// Equal returns whether this failure domain represents the same
+	// platform specific configuration as other. Unlike comparing the
+	// String() output, Equal is not affected by changes to the
+	// human-readable representation.
+	Equal(other FailureDomain) bool
+
+	// Hash returns a deterministic identifier for the failure domain,
+	// derived from its platform specific fields. It is suitable for use
+	// as a map key when diffing sets of failure domains.
+	Hash() string
+}
+
+// Diff compares two lists of FailureDomains, keyed by Hash, and returns the
+// failure domains present in desired but not current (added) and those
+// present in current but not desired (removed). It allows the reconciler
+// to make idempotent decisions about failure domains without depending on
+// the human-readable String() representation.
+func Diff(current, desired []FailureDomain) (added, removed []FailureDomain) {
+	currentByHash := make(map[string]FailureDomain, len(current))
+	for _, fd := range current {
+		currentByHash[fd.Hash()] = fd
+	}
+
+	desiredByHash := make(map[string]FailureDomain, len(desired))
+	for _, fd := range desired {
+		desiredByHash[fd.Hash()] = fd
+	}
+
+	for hash, fd := range desiredByHash {
+		if _, ok := currentByHash[hash]; !ok {
+			added = append(added, fd)
+		}
+	}
+
+	for hash, fd := range currentByHash {
+		if _, ok := desiredByHash[hash]; !ok {
+			removed = append(removed, fd)
+		}
+	}
+
+	sortByHash(added)
+	sortByHash(removed)
+
+	return added, removed
+}
+
+// sortByHash orders a list of failure domains by their Hash so that Diff
+// returns a deterministic order regardless of map iteration order.
+func sortByHash(failureDomains []FailureDomain) {
+	sort.Slice(failureDomains, func(i, j int) bool {
+		return failureDomains[i].Hash() < failureDomains[j].Hash()
+	})
+}
+
+// NewFailureDomains constructs a list of FailureDomains from the
+// ControlPlaneMachineSet failure domain configuration.
+func NewFailureDomains(failureDomains machinev1.FailureDomains, opts ...Option) ([]FailureDomain, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	switch failureDomains.Platform {
+	case "":
+		return nil, nil
+	case configv1.AWSPlatformType:
+		return newAWSFailureDomains(failureDomains, o)
+	case configv1.AzurePlatformType:
+		return newAzureFailureDomains(failureDomains)
+	case configv1.GCPPlatformType:
+		return newGCPFailureDomains(failureDomains)
+	case configv1.VSpherePlatformType:
+		return newVSphereFailureDomains(failureDomains)
+	default:
+		return []FailureDomain{}, fmt.Errorf("unsupported platform type: %s", failureDomains.Platform)
+	}
+}
+
+// newAWSFailureDomains constructs FailureDomains from the AWS failure domain
+// configuration. When a SubnetResolver has been provided, filter- and
+// ARN-typed subnet references are additionally resolved to concrete subnet
+// IDs, so that the resulting failure domains can be compared for equality
+// with those constructed from existing Machines.
+func newAWSFailureDomains(failureDomains machinev1.FailureDomains, o *options) ([]FailureDomain, error) {
+	if failureDomains.AWS == nil {
+		return []FailureDomain{}, errMissingFailureDomainConfiguration
+	}
+
+	fds := make([]FailureDomain, 0, len(*failureDomains.AWS))
+
+	for _, fd := range *failureDomains.AWS {
+		fd := fd
+
+		resolvedSubnetID, err := resolveAWSSubnetID(fd.Subnet, o.subnetResolver)
+		if err != nil {
+			return nil, fmt.Errorf("error resolving subnet for availability zone %s: %w", fd.AvailabilityZone, err)
+		}
+
+		fds = append(fds, failureDomain{
+			platformType:        configv1.AWSPlatformType,
+			aws:                 &fd,
+			awsResolvedSubnetID: resolvedSubnetID,
+		})
+	}
+
+	return fds, nil
+}
+
+// newAzureFailureDomains constructs FailureDomains from the Azure failure
+// domain configuration.
+func newAzureFailureDomains(failureDomains machinev1.FailureDomains) ([]FailureDomain, error) {
+	if failureDomains.Azure == nil {
+		return []FailureDomain{}, errMissingFailureDomainConfiguration
+	}
+
+	fds := make([]FailureDomain, 0, len(*failureDomains.Azure))
+
+	for _, fd := range *failureDomains.Azure {
+		fd := fd
+
+		fds = append(fds, failureDomain{
+			platformType: configv1.AzurePlatformType,
+			azure:        &fd,
+		})
+	}
+
+	return fds, nil
+}
+
+// newGCPFailureDomains constructs FailureDomains from the GCP failure
+// domain configuration.
+func newGCPFailureDomains(failureDomains machinev1.FailureDomains) ([]FailureDomain, error) {
+	if failureDomains.GCP == nil {
+		return []FailureDomain{}, errMissingFailureDomainConfiguration
+	}
+
+	fds := make([]FailureDomain, 0, len(*failureDomains.GCP))
+
+	for _, fd := range *failureDomains.GCP {
+		fd := fd
+
+		fds = append(fds, failureDomain{
+			platformType: configv1.GCPPlatformType,
+			gcp:          &fd,
+		})
+	}
+
+	return fds, nil
+}
+
+// newVSphereFailureDomains constructs FailureDomains from the vSphere
+// failure domain configuration.
+func newVSphereFailureDomains(failureDomains machinev1.FailureDomains) ([]FailureDomain, error) {
+	if failureDomains.VSphere == nil {
+		return []FailureDomain{}, errMissingFailureDomainConfiguration
+	}
+
+	fds := make([]FailureDomain, 0, len(*failureDomains.VSphere))
+
+	for _, fd := range *failureDomains.VSphere {
+		fd := fd
+
+		fds = append(fds, failureDomain{
+			platformType: configv1.VSpherePlatformType,
+			vsphere:      &fd,
+		})
+	}
+
+	return fds, nil
+}
+
+// options holds the optional configuration accepted by NewFailureDomains
+// and NewFailureDomainsFromMachines.
+type options struct {
+	// vsphereFailureDomains are the named vSphere failure domain topologies,
+	// as configured on the infrastructure resource, used to reverse map a
+	// Machine's provider spec back to the failure domain it was created
+	// from.
+	vsphereFailureDomains []machinev1.VSphereFailureDomain
+
+	// subnetResolver, when set, is used to resolve AWS filter- and
+	// ARN-typed subnet references to concrete subnet IDs.
+	subnetResolver SubnetResolver
+}
+
+// Option allows optional, platform specific, configuration to be passed to
+// NewFailureDomains and NewFailureDomainsFromMachines without changing
+// their signature for every platform that needs it.
+type Option func(*options)
+
+// WithVSphereFailureDomains provides the named vSphere failure domain
+// topologies that NewFailureDomainsFromMachines should match existing
+// control plane Machines against. It is required when reconstructing
+// vSphere failure domains from Machines, since a Machine's provider spec
+// does not record the failure domain name directly.
+func WithVSphereFailureDomains(failureDomains []machinev1.VSphereFailureDomain) Option {
+	return func(o *options) {
+		o.vsphereFailureDomains = failureDomains
+	}
+}
+
+// SubnetResolver resolves an AWS subnet reference, as used within a
+// failure domain or provider spec, to the ID of a single matching subnet.
+// Implementations are expected to call out to EC2 DescribeSubnets.
+type SubnetResolver interface {
+	ResolveSubnet(subnet machinev1.AWSResourceReference) (string, error)
+}
+
+// WithSubnetResolver provides a SubnetResolver that NewFailureDomains and
+// NewFailureDomainsFromMachines should use to resolve filter- and
+// ARN-typed AWS subnet references to concrete subnet IDs. Without it, a
+// ControlPlaneMachineSet whose failure domains reference subnets by filter
+// cannot be diffed against Machines whose provider specs store subnets by
+// ID.
+func WithSubnetResolver(resolver SubnetResolver) Option {
+	return func(o *options) {
+		o.subnetResolver = resolver
+	}
+}
+
+// resolveAWSSubnetID resolves subnet to a concrete subnet ID. Subnets
+// already referenced by ID are returned as-is without consulting the
+// resolver. When no resolver has been configured, filter- and ARN-typed
+// subnets cannot be resolved and a nil ID is returned; the original
+// reference is retained for round-tripping but cannot be compared for
+// equality against ID-based failure domains.
+func resolveAWSSubnetID(subnet *machinev1.AWSResourceReference, resolver SubnetResolver) (*string, error) {
+	if subnet == nil {
+		return nil, nil
+	}
+
+	if subnet.Type == machinev1.AWSIDReferenceType {
+		return subnet.ID, nil
+	}
+
+	if resolver == nil {
+		return nil, nil
+	}
+
+	id, err := resolver.ResolveSubnet(*subnet)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving subnet: %w", err)
+	}
+
+	return &id, nil
+}
+
+// NewFailureDomainsFromMachines constructs a list of FailureDomains by
+// inspecting the provider spec of each of the given Machines. This allows
+// the reconciler to determine the failure domains currently in use by the
+// control plane when no failure domain configuration is present.
+func NewFailureDomainsFromMachines(machines []machinev1beta1.Machine, platform configv1.PlatformType, opts ...Option) ([]FailureDomain, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	fds := make([]FailureDomain, 0, len(machines))
+
+	for _, machine := range machines {
+		fd, err := failureDomainFromMachine(machine, platform, o)
+		if err != nil {
+			return nil, fmt.Errorf("error determining failure domain for machine %s: %w", machine.Name, err)
+		}
+
+		fds = append(fds, fd)
+	}
+
+	return fds, nil
+}
+
+// failureDomainFromMachine constructs a single FailureDomain from the
+// provider spec of the given Machine.
+func failureDomainFromMachine(machine machinev1beta1.Machine, platform configv1.PlatformType, o *options) (FailureDomain, error) {
+	if machine.Spec.ProviderSpec.Value == nil {
+		return nil, errMachineMissingProviderSpec
+	}
+
+	switch platform {
+	case configv1.AWSPlatformType:
+		return awsFailureDomainFromMachine(machine, o.subnetResolver)
+	case configv1.AzurePlatformType:
+		return azureFailureDomainFromMachine(machine)
+	case configv1.GCPPlatformType:
+		return gcpFailureDomainFromMachine(machine)
+	case configv1.VSpherePlatformType:
+		return vsphereFailureDomainFromMachine(machine, o.vsphereFailureDomains)
+	default:
+		return nil, fmt.Errorf("unsupported platform type: %s", platform)
+	}
+}
+
+// awsFailureDomainFromMachine decodes the AWS provider spec from the given
+// Machine and constructs a FailureDomain from it. When a SubnetResolver
+// has been provided, a filter- or ARN-typed subnet reference is
+// additionally resolved to a concrete subnet ID; in practice Machines
+// almost always store their subnet by ID already.
+func awsFailureDomainFromMachine(machine machinev1beta1.Machine, resolver SubnetResolver) (FailureDomain, error) {
+	providerSpec := &machinev1beta1.AWSMachineProviderSpec{}
+	if err := json.Unmarshal(machine.Spec.ProviderSpec.Value.Raw, providerSpec); err != nil {
+		return nil, fmt.Errorf("error unmarshalling AWS provider spec: %w", err)
+	}
+
+	subnet := awsResourceReferenceFromProviderSpec(providerSpec.Subnet)
+
+	resolvedSubnetID, err := resolveAWSSubnetID(subnet, resolver)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving subnet for machine %s: %w", machine.Name, err)
+	}
+
+	aws := &machinev1.AWSFailureDomain{
+		AvailabilityZone: providerSpec.Placement.AvailabilityZone,
+		Subnet:           subnet,
+	}
+
+	return failureDomain{
+		platformType:        configv1.AWSPlatformType,
+		aws:                 aws,
+		awsResolvedSubnetID: resolvedSubnetID,
+	}, nil
+}
+
+// awsResourceReferenceFromProviderSpec converts the AWSResourceReference
+// used within the provider spec to the one used within the failure domain
+// configuration. These are structurally identical today but are distinct
+// API types.
+func awsResourceReferenceFromProviderSpec(ref machinev1beta1.AWSResourceReference) *machinev1.AWSResourceReference {
+	return &machinev1.AWSResourceReference{
+		Type:    machinev1.AWSResourceReferenceType(ref.Type),
+		ID:      ref.ID,
+		ARN:     ref.ARN,
+		Filters: (*[]machinev1.AWSResourceFilter)(ref.Filters),
+	}
+}
+
+// azureFailureDomainFromMachine decodes the Azure provider spec from the
+// given Machine and constructs a FailureDomain from it.
+func azureFailureDomainFromMachine(machine machinev1beta1.Machine) (FailureDomain, error) {
+	providerSpec := &machinev1beta1.AzureMachineProviderSpec{}
+	if err := json.Unmarshal(machine.Spec.ProviderSpec.Value.Raw, providerSpec); err != nil {
+		return nil, fmt.Errorf("error unmarshalling Azure provider spec: %w", err)
+	}
+
+	azure := &machinev1.AzureFailureDomain{
+		Zone:   derefString(providerSpec.Zone),
+		Subnet: providerSpec.Subnet,
+	}
+
+	return failureDomain{
+		platformType: configv1.AzurePlatformType,
+		azure:        azure,
+	}, nil
+}
+
+// gcpFailureDomainFromMachine decodes the GCP provider spec from the given
+// Machine and constructs a FailureDomain from it.
+func gcpFailureDomainFromMachine(machine machinev1beta1.Machine) (FailureDomain, error) {
+	providerSpec := &machinev1beta1.GCPMachineProviderSpec{}
+	if err := json.Unmarshal(machine.Spec.ProviderSpec.Value.Raw, providerSpec); err != nil {
+		return nil, fmt.Errorf("error unmarshalling GCP provider spec: %w", err)
+	}
+
+	gcp := &machinev1.GCPFailureDomain{
+		Zone: providerSpec.Zone,
+	}
+
+	if len(providerSpec.NetworkInterfaces) > 0 {
+		gcp.Subnet = providerSpec.NetworkInterfaces[0].Subnetwork
+	}
+
+	return failureDomain{
+		platformType: configv1.GCPPlatformType,
+		gcp:          gcp,
+	}, nil
+}
+
+// vsphereFailureDomainFromMachine decodes the vSphere provider spec from
+// the given Machine and matches its workspace (server, datacenter,
+// datastore, resource pool, folder and networks) against the configured
+// vSphere failure domain topologies to determine which named failure
+// domain the Machine belongs to.
+func vsphereFailureDomainFromMachine(machine machinev1beta1.Machine, topologies []machinev1.VSphereFailureDomain) (FailureDomain, error) {
+	providerSpec := &machinev1beta1.VSphereMachineProviderSpec{}
+	if err := json.Unmarshal(machine.Spec.ProviderSpec.Value.Raw, providerSpec); err != nil {
+		return nil, fmt.Errorf("error unmarshalling vSphere provider spec: %w", err)
+	}
+
+	for _, topology := range topologies {
+		if vsphereWorkspaceMatchesTopology(providerSpec, topology) {
+			fd := topology
+
+			return failureDomain{
+				platformType: configv1.VSpherePlatformType,
+				vsphere:      &fd,
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no vSphere failure domain topology matches machine %s", machine.Name)
+}
+
+// vsphereWorkspaceMatchesTopology compares a Machine's vSphere workspace and
+// network devices against a configured failure domain. The vCenter Server is
+// compared in addition to the Topology fields because distinct failure
+// domains in a multi-vCenter deployment can otherwise share an identical
+// Datacenter/Datastore/ResourcePool/Folder path.
+func vsphereWorkspaceMatchesTopology(providerSpec *machinev1beta1.VSphereMachineProviderSpec, failureDomain machinev1.VSphereFailureDomain) bool {
+	if providerSpec.Workspace == nil {
+		return false
+	}
+
+	topology := failureDomain.Topology
+
+	if providerSpec.Workspace.Server != failureDomain.Server ||
+		providerSpec.Workspace.Datacenter != topology.Datacenter ||
+		providerSpec.Workspace.Datastore != topology.Datastore ||
+		providerSpec.Workspace.ResourcePool != topology.ResourcePool ||
+		providerSpec.Workspace.Folder != topology.Folder {
+		return false
+	}
+
+	for _, network := range topology.Networks {
+		if !vsphereHasNetworkDevice(providerSpec.Network.Devices, network) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// vsphereHasNetworkDevice returns true when one of the Machine's network
+// devices is attached to the given network name.
+func vsphereHasNetworkDevice(devices []machinev1beta1.NetworkDeviceSpec, network string) bool {
+	for _, device := range devices {
+		if device.NetworkName == network {
+			return true
+		}
+	}
+
+	return false
+}
+
+// failureDomain is the concrete implementation of the FailureDomain
+// interface. Only one of the platform specific fields should be set,
+// determined by the platformType.
+type failureDomain struct {
+	platformType configv1.PlatformType
+
+	aws     *machinev1.AWSFailureDomain
+	azure   *machinev1.AzureFailureDomain
+	gcp     *machinev1.GCPFailureDomain
+	vsphere *machinev1.VSphereFailureDomain
+
+	// awsResolvedSubnetID is the concrete subnet ID resolved from aws.Subnet
+	// by a SubnetResolver, when one was provided at construction time. It
+	// takes precedence over aws.Subnet when hashing, so that a failure
+	// domain referencing a subnet by filter can be considered equal to one
+	// referencing the same subnet by ID.
+	awsResolvedSubnetID *string
+}
+
+// String returns a human readable representation of the failure domain,
+// intended for use in logging and error messages.
+func (f failureDomain) String() string {
+	switch f.platformType {
+	case configv1.AWSPlatformType:
+		return f.stringAWS()
+	case configv1.AzurePlatformType:
+		return f.stringAzure()
+	case configv1.GCPPlatformType:
+		return f.stringGCP()
+	case configv1.VSpherePlatformType:
+		return f.stringVSphere()
+	default:
+		return ""
+	}
+}
+
+// Equal returns whether this failure domain represents the same platform
+// specific configuration as other.
+func (f failureDomain) Equal(other FailureDomain) bool {
+	if other == nil {
+		return false
+	}
+
+	return f.Hash() == other.Hash()
+}
+
+// Hash returns a deterministic identifier for the failure domain, derived
+// from a canonical JSON encoding of its platform specific fields. The
+// platform type is included so that, hypothetically, two empty failure
+// domains on different platforms never collide.
+func (f failureDomain) Hash() string {
+	var platformFields interface{}
+
+	switch f.platformType {
+	case configv1.AWSPlatformType:
+		platformFields = f.hashFieldsAWS()
+	case configv1.AzurePlatformType:
+		platformFields = f.azure
+	case configv1.GCPPlatformType:
+		platformFields = f.gcp
+	case configv1.VSpherePlatformType:
+		platformFields = f.vsphere
+	}
+
+	// The platform specific types are static, well-formed structs, so
+	// marshalling them can only fail in the event of a programming error.
+	raw, err := json.Marshal(platformFields)
+	if err != nil {
+		panic(fmt.Errorf("error marshalling failure domain: %w", err))
+	}
+
+	sum := sha256.Sum256(append([]byte(f.platformType+":"), raw...))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// awsHashFields is the canonical, JSON-marshalled representation of an AWS
+// failure domain used by Hash. ResolvedSubnetID is preferred over the raw
+// Subnet reference, where available, so that filter- and ID-based
+// references to the same subnet hash identically. An ID-typed Subnet
+// counts as resolved to its own ID.
+type awsHashFields struct {
+	AvailabilityZone string
+	ResolvedSubnetID *string                         `json:",omitempty"`
+	Subnet           *machinev1.AWSResourceReference `json:",omitempty"`
+}
+
+// hashFieldsAWS builds the canonical fields used to hash an AWS failure
+// domain.
+func (f failureDomain) hashFieldsAWS() awsHashFields {
+	if f.aws == nil {
+		return awsHashFields{}
+	}
+
+	fields := awsHashFields{AvailabilityZone: f.aws.AvailabilityZone}
+
+	switch {
+	case f.awsResolvedSubnetID != nil:
+		fields.ResolvedSubnetID = f.awsResolvedSubnetID
+	case f.aws.Subnet != nil && f.aws.Subnet.Type == machinev1.AWSIDReferenceType:
+		fields.ResolvedSubnetID = f.aws.Subnet.ID
+	default:
+		fields.Subnet = f.aws.Subnet
+	}
+
+	return fields
+}
+
+// stringAWS renders the AWS failure domain fields.
+func (f failureDomain) stringAWS() string {
+	if f.aws == nil {
+		return "AWSFailureDomain{}"
+	}
+
+	fields := []string{}
+
+	if f.aws.AvailabilityZone != "" {
+		fields = append(fields, fmt.Sprintf("AvailabilityZone:%s", f.aws.AvailabilityZone))
+	}
+
+	if f.aws.Subnet != nil {
+		fields = append(fields, fmt.Sprintf("Subnet:%s", stringAWSResourceReference(*f.aws.Subnet)))
+	}
+
+	return fmt.Sprintf("AWSFailureDomain{%s}", strings.Join(fields, ", "))
+}
+
+// stringAWSResourceReference renders an AWSResourceReference according to
+// its reference type.
+func stringAWSResourceReference(ref machinev1.AWSResourceReference) string {
+	switch ref.Type {
+	case machinev1.AWSIDReferenceType:
+		return fmt.Sprintf("{Type:id, Value:%s}", derefString(ref.ID))
+	case machinev1.AWSARNReferenceType:
+		return fmt.Sprintf("{Type:arn, Value:%s}", derefString(ref.ARN))
+	case machinev1.AWSFiltersReferenceType:
+		return fmt.Sprintf("{Type:filters, Value:%+v}", ref.Filters)
+	default:
+		return ""
+	}
+}
+
+// derefString safely dereferences a string pointer, returning the empty
+// string when it is nil.
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
+
+// stringAzure renders the Azure failure domain fields.
+func (f failureDomain) stringAzure() string {
+	if f.azure == nil {
+		return "AzureFailureDomain{}"
+	}
+
+	fields := []string{fmt.Sprintf("Zone:%s", f.azure.Zone)}
+
+	if f.azure.Subnet != "" {
+		fields = append(fields, fmt.Sprintf("Subnet:%s", f.azure.Subnet))
+	}
+
+	return fmt.Sprintf("AzureFailureDomain{%s}", strings.Join(fields, ", "))
+}
+
+// stringGCP renders the GCP failure domain fields.
+func (f failureDomain) stringGCP() string {
+	if f.gcp == nil {
+		return "GCPFailureDomain{}"
+	}
+
+	fields := []string{fmt.Sprintf("Zone:%s", f.gcp.Zone)}
+
+	if f.gcp.Subnet != "" {
+		fields = append(fields, fmt.Sprintf("Subnetwork:%s", f.gcp.Subnet))
+	}
+
+	return fmt.Sprintf("GCPFailureDomain{%s}", strings.Join(fields, ", "))
+}
+
+// stringVSphere renders the vSphere failure domain fields. Unlike the
+// other platforms, the vSphere failure domain is identified solely by its
+// configured name; the topology fields are only used for matching.
+func (f failureDomain) stringVSphere() string {
+	if f.vsphere == nil {
+		return "VSphereFailureDomain{}"
+	}
+
+	return fmt.Sprintf("VSphereFailureDomain{Name:%s}", f.vsphere.Name)
+}
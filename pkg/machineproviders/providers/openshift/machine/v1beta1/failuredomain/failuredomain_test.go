@@ -108,6 +108,135 @@ var _ = Describe("FailureDomains", func() {
 				Expect(failureDomains).To(BeEmpty())
 			})
 		})
+
+		Context("With Azure failure domain configuration", func() {
+			var failureDomains []FailureDomain
+			var err error
+
+			BeforeEach(func() {
+				config := resourcebuilder.AzureFailureDomains().BuildFailureDomains()
+
+				failureDomains, err = NewFailureDomains(config)
+			})
+
+			It("should not error", func() {
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should construct a list of failure domains", func() {
+				Expect(failureDomains).To(ConsistOf(
+					HaveField("String()", "AzureFailureDomain{Zone:1, Subnet:azure-subnet-12345678}"),
+					HaveField("String()", "AzureFailureDomain{Zone:2, Subnet:azure-subnet-12345678}"),
+					HaveField("String()", "AzureFailureDomain{Zone:3, Subnet:azure-subnet-12345678}"),
+				))
+			})
+		})
+
+		Context("With invalid Azure failure domain configuration", func() {
+			var failureDomains []FailureDomain
+			var err error
+
+			BeforeEach(func() {
+				config := resourcebuilder.AzureFailureDomains().BuildFailureDomains()
+				config.Azure = nil
+
+				failureDomains, err = NewFailureDomains(config)
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(MatchError("missing failure domain configuration"))
+			})
+
+			It("returns an empty list of failure domains", func() {
+				Expect(failureDomains).To(BeEmpty())
+			})
+		})
+
+		Context("With GCP failure domain configuration", func() {
+			var failureDomains []FailureDomain
+			var err error
+
+			BeforeEach(func() {
+				config := resourcebuilder.GCPFailureDomains().BuildFailureDomains()
+
+				failureDomains, err = NewFailureDomains(config)
+			})
+
+			It("should not error", func() {
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should construct a list of failure domains", func() {
+				Expect(failureDomains).To(ConsistOf(
+					HaveField("String()", "GCPFailureDomain{Zone:us-central1-a, Subnetwork:gcp-subnet-12345678}"),
+					HaveField("String()", "GCPFailureDomain{Zone:us-central1-b, Subnetwork:gcp-subnet-12345678}"),
+					HaveField("String()", "GCPFailureDomain{Zone:us-central1-c, Subnetwork:gcp-subnet-12345678}"),
+				))
+			})
+		})
+
+		Context("With invalid GCP failure domain configuration", func() {
+			var failureDomains []FailureDomain
+			var err error
+
+			BeforeEach(func() {
+				config := resourcebuilder.GCPFailureDomains().BuildFailureDomains()
+				config.GCP = nil
+
+				failureDomains, err = NewFailureDomains(config)
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(MatchError("missing failure domain configuration"))
+			})
+
+			It("returns an empty list of failure domains", func() {
+				Expect(failureDomains).To(BeEmpty())
+			})
+		})
+
+		Context("With vSphere failure domain configuration", func() {
+			var failureDomains []FailureDomain
+			var err error
+
+			BeforeEach(func() {
+				config := resourcebuilder.VSphereFailureDomains().BuildFailureDomains()
+
+				failureDomains, err = NewFailureDomains(config)
+			})
+
+			It("should not error", func() {
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should construct a list of failure domains", func() {
+				Expect(failureDomains).To(ConsistOf(
+					HaveField("String()", "VSphereFailureDomain{Name:us-east-1a}"),
+					HaveField("String()", "VSphereFailureDomain{Name:us-east-1b}"),
+					HaveField("String()", "VSphereFailureDomain{Name:us-east-1c}"),
+				))
+			})
+		})
+
+		Context("With invalid vSphere failure domain configuration", func() {
+			var failureDomains []FailureDomain
+			var err error
+
+			BeforeEach(func() {
+				config := resourcebuilder.VSphereFailureDomains().BuildFailureDomains()
+				config.VSphere = nil
+
+				failureDomains, err = NewFailureDomains(config)
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(MatchError("missing failure domain configuration"))
+			})
+
+			It("returns an empty list of failure domains", func() {
+				Expect(failureDomains).To(BeEmpty())
+			})
+		})
 	})
 
 	Context("NewFailureDomainsFromMachines", func() {
@@ -193,6 +322,149 @@ var _ = Describe("FailureDomains", func() {
 				Expect(failureDomains).To(BeNil())
 			})
 		})
+
+		Context("With Azure machines", func() {
+			var failureDomains []FailureDomain
+			var err error
+
+			BeforeEach(func() {
+				providerSpec := resourcebuilder.AzureProviderSpec()
+				machines := []machinev1beta1.Machine{}
+				for _, zone := range []string{"1", "2", "3"} {
+					ps := providerSpec.WithZone(zone)
+					machines = append(machines, *resourcebuilder.Machine().WithProviderSpecBuilder(ps).Build())
+				}
+				failureDomains, err = NewFailureDomainsFromMachines(machines, configv1.AzurePlatformType)
+			})
+
+			It("should not error", func() {
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should construct a list of failure domains", func() {
+				Expect(failureDomains).To(ConsistOf(
+					HaveField("String()", "AzureFailureDomain{Zone:1, Subnet:azure-subnet-12345678}"),
+					HaveField("String()", "AzureFailureDomain{Zone:2, Subnet:azure-subnet-12345678}"),
+					HaveField("String()", "AzureFailureDomain{Zone:3, Subnet:azure-subnet-12345678}"),
+				))
+			})
+		})
+
+		Context("With GCP machines", func() {
+			var failureDomains []FailureDomain
+			var err error
+
+			BeforeEach(func() {
+				providerSpec := resourcebuilder.GCPProviderSpec()
+				machines := []machinev1beta1.Machine{}
+				for _, zone := range []string{"us-central1-a", "us-central1-b", "us-central1-c"} {
+					ps := providerSpec.WithZone(zone)
+					machines = append(machines, *resourcebuilder.Machine().WithProviderSpecBuilder(ps).Build())
+				}
+				failureDomains, err = NewFailureDomainsFromMachines(machines, configv1.GCPPlatformType)
+			})
+
+			It("should not error", func() {
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should construct a list of failure domains", func() {
+				Expect(failureDomains).To(ConsistOf(
+					HaveField("String()", "GCPFailureDomain{Zone:us-central1-a, Subnetwork:gcp-subnet-12345678}"),
+					HaveField("String()", "GCPFailureDomain{Zone:us-central1-b, Subnetwork:gcp-subnet-12345678}"),
+					HaveField("String()", "GCPFailureDomain{Zone:us-central1-c, Subnetwork:gcp-subnet-12345678}"),
+				))
+			})
+		})
+
+		Context("With vSphere machines", func() {
+			var failureDomains []FailureDomain
+			var err error
+
+			BeforeEach(func() {
+				vsphereFailureDomains := resourcebuilder.VSphereFailureDomains()
+				machines := []machinev1beta1.Machine{}
+
+				for _, name := range []string{"us-east-1a", "us-east-1b", "us-east-1c"} {
+					fd := resourcebuilder.VSphereFailureDomain().WithName(name)
+					ps := resourcebuilder.VSphereProviderSpec().WithFailureDomainTopology(fd)
+					machines = append(machines, *resourcebuilder.Machine().WithProviderSpecBuilder(ps).Build())
+				}
+
+				failureDomains, err = NewFailureDomainsFromMachines(
+					machines,
+					configv1.VSpherePlatformType,
+					WithVSphereFailureDomains(*vsphereFailureDomains.WithNames([]string{"us-east-1a", "us-east-1b", "us-east-1c"}).BuildFailureDomains().VSphere),
+				)
+			})
+
+			It("should not error", func() {
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should construct a list of failure domains", func() {
+				Expect(failureDomains).To(ConsistOf(
+					HaveField("String()", "VSphereFailureDomain{Name:us-east-1a}"),
+					HaveField("String()", "VSphereFailureDomain{Name:us-east-1b}"),
+					HaveField("String()", "VSphereFailureDomain{Name:us-east-1c}"),
+				))
+			})
+		})
+
+		Context("With vSphere machines sharing topology across different vCenter servers", func() {
+			var failureDomains []FailureDomain
+			var err error
+
+			BeforeEach(func() {
+				failureDomainA := resourcebuilder.VSphereFailureDomain().WithName("us-east-1a").WithServer("vcenter-a.example.com")
+				failureDomainB := resourcebuilder.VSphereFailureDomain().WithName("us-east-1a").WithServer("vcenter-b.example.com")
+
+				machines := []machinev1beta1.Machine{
+					*resourcebuilder.Machine().WithProviderSpecBuilder(
+						resourcebuilder.VSphereProviderSpec().WithFailureDomainTopology(failureDomainB),
+					).Build(),
+				}
+
+				failureDomains, err = NewFailureDomainsFromMachines(
+					machines,
+					configv1.VSpherePlatformType,
+					WithVSphereFailureDomains([]machinev1.VSphereFailureDomain{
+						*failureDomainA.Build(),
+						*failureDomainB.Build(),
+					}),
+				)
+			})
+
+			It("should not error", func() {
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("matches the failure domain with the machine's vCenter server, not merely the first sharing its topology", func() {
+				Expect(failureDomains).To(ConsistOf(
+					HaveField("Hash()", Equal(failureDomain{platformType: configv1.VSpherePlatformType, vsphere: failureDomainB.Build()}.Hash())),
+				))
+			})
+		})
+
+		Context("With vSphere machines that match no configured topology", func() {
+			var err error
+
+			BeforeEach(func() {
+				fd := resourcebuilder.VSphereFailureDomain().WithName("unknown").WithNetworks([]string{"other-network"})
+				ps := resourcebuilder.VSphereProviderSpec().WithFailureDomainTopology(fd)
+				machine := *resourcebuilder.Machine().WithProviderSpecBuilder(ps).Build()
+
+				_, err = NewFailureDomainsFromMachines(
+					[]machinev1beta1.Machine{machine},
+					configv1.VSpherePlatformType,
+					WithVSphereFailureDomains(*resourcebuilder.VSphereFailureDomains().WithNames([]string{"us-east-1a"}).BuildFailureDomains().VSphere),
+				)
+			})
+
+			It("returns a descriptive error", func() {
+				Expect(err).To(MatchError(ContainSubstring("no vSphere failure domain topology matches machine")))
+			})
+		})
 	})
 
 	Context("an AWS failure domain", func() {
@@ -264,4 +536,222 @@ var _ = Describe("FailureDomains", func() {
 			})
 		})
 	})
+
+	Context("an Azure failure domain", func() {
+		var fd failureDomain
+
+		BeforeEach(func() {
+			fd = failureDomain{
+				platformType: configv1.AzurePlatformType,
+			}
+		})
+
+		Context("with a zone and subnet", func() {
+			BeforeEach(func() {
+				fd.azure = resourcebuilder.AzureFailureDomain().WithZone("1").WithSubnet("my-subnet").Build()
+			})
+
+			It("returns the zone and subnet for String()", func() {
+				Expect(fd.String()).To(Equal("AzureFailureDomain{Zone:1, Subnet:my-subnet}"))
+			})
+		})
+
+		Context("with no subnet", func() {
+			BeforeEach(func() {
+				fd.azure = resourcebuilder.AzureFailureDomain().WithZone("1").WithSubnet("").Build()
+			})
+
+			It("returns just the zone for String()", func() {
+				Expect(fd.String()).To(Equal("AzureFailureDomain{Zone:1}"))
+			})
+		})
+	})
+
+	Context("a GCP failure domain", func() {
+		var fd failureDomain
+
+		BeforeEach(func() {
+			fd = failureDomain{
+				platformType: configv1.GCPPlatformType,
+			}
+		})
+
+		Context("with a zone and subnetwork", func() {
+			BeforeEach(func() {
+				fd.gcp = resourcebuilder.GCPFailureDomain().WithZone("us-central1-a").WithSubnet("my-subnet").Build()
+			})
+
+			It("returns the zone and subnetwork for String()", func() {
+				Expect(fd.String()).To(Equal("GCPFailureDomain{Zone:us-central1-a, Subnetwork:my-subnet}"))
+			})
+		})
+
+		Context("with no subnetwork", func() {
+			BeforeEach(func() {
+				fd.gcp = resourcebuilder.GCPFailureDomain().WithZone("us-central1-a").WithSubnet("").Build()
+			})
+
+			It("returns just the zone for String()", func() {
+				Expect(fd.String()).To(Equal("GCPFailureDomain{Zone:us-central1-a}"))
+			})
+		})
+	})
+
+	Context("a vSphere failure domain", func() {
+		var fd failureDomain
+
+		BeforeEach(func() {
+			fd = failureDomain{
+				platformType: configv1.VSpherePlatformType,
+				vsphere:      resourcebuilder.VSphereFailureDomain().WithName("us-east-1a").Build(),
+			}
+		})
+
+		It("returns the name for String()", func() {
+			Expect(fd.String()).To(Equal("VSphereFailureDomain{Name:us-east-1a}"))
+		})
+	})
+
+	Context("with a SubnetResolver", func() {
+		var resolver *fakeSubnetResolver
+		var filterSubnet machinev1.AWSResourceReference
+
+		BeforeEach(func() {
+			resolver = &fakeSubnetResolver{subnetID: "subnet-12345678"}
+			filterSubnet = machinev1.AWSResourceReference{
+				Type: machinev1.AWSFiltersReferenceType,
+				Filters: &[]machinev1.AWSResourceFilter{
+					{
+						Name:   "tag:Name",
+						Values: []string{"subnet-us-east-1a"},
+					},
+				},
+			}
+		})
+
+		It("resolves filter-typed subnets constructed from configuration", func() {
+			config := resourcebuilder.AWSFailureDomains().WithAvailabilityZones([]string{"us-east-1a"}).BuildFailureDomains()
+			(*config.AWS)[0].Subnet = &filterSubnet
+
+			failureDomains, err := NewFailureDomains(config, WithSubnetResolver(resolver))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(failureDomains).To(HaveLen(1))
+
+			subnetID := "subnet-12345678"
+			fromMachine := failureDomain{
+				platformType: configv1.AWSPlatformType,
+				aws: resourcebuilder.AWSFailureDomain().WithAvailabilityZone("us-east-1a").WithSubnet(machinev1.AWSResourceReference{
+					Type: machinev1.AWSIDReferenceType,
+					ID:   &subnetID,
+				}).Build(),
+			}
+
+			Expect(failureDomains[0].Equal(fromMachine)).To(BeTrue())
+		})
+
+		It("leaves filter-typed subnets unresolved, and therefore unequal to ID based ones, without a resolver", func() {
+			config := resourcebuilder.AWSFailureDomains().WithAvailabilityZones([]string{"us-east-1a"}).BuildFailureDomains()
+			(*config.AWS)[0].Subnet = &filterSubnet
+
+			failureDomains, err := NewFailureDomains(config)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(failureDomains).To(HaveLen(1))
+
+			subnetID := "subnet-12345678"
+			fromMachine := failureDomain{
+				platformType: configv1.AWSPlatformType,
+				aws: resourcebuilder.AWSFailureDomain().WithAvailabilityZone("us-east-1a").WithSubnet(machinev1.AWSResourceReference{
+					Type: machinev1.AWSIDReferenceType,
+					ID:   &subnetID,
+				}).Build(),
+			}
+
+			Expect(failureDomains[0].Equal(fromMachine)).To(BeFalse())
+		})
+	})
+
+	Context("Equal and Hash", func() {
+		var fdA, fdB failureDomain
+
+		BeforeEach(func() {
+			fdA = failureDomain{
+				platformType: configv1.AWSPlatformType,
+				aws:          resourcebuilder.AWSFailureDomain().WithAvailabilityZone("us-east-1a").Build(),
+			}
+			fdB = failureDomain{
+				platformType: configv1.AWSPlatformType,
+				aws:          resourcebuilder.AWSFailureDomain().WithAvailabilityZone("us-east-1a").Build(),
+			}
+		})
+
+		It("treats identically configured failure domains as equal", func() {
+			Expect(fdA.Equal(fdB)).To(BeTrue())
+			Expect(fdA.Hash()).To(Equal(fdB.Hash()))
+		})
+
+		It("treats differently configured failure domains as not equal", func() {
+			fdB.aws = resourcebuilder.AWSFailureDomain().WithAvailabilityZone("us-east-1b").Build()
+
+			Expect(fdA.Equal(fdB)).To(BeFalse())
+			Expect(fdA.Hash()).ToNot(Equal(fdB.Hash()))
+		})
+
+		It("treats failure domains with the same availability zone but different subnets as not equal", func() {
+			// Two AWS failure domains in the same availability zone, only
+			// one of which references a subnet, should not be equal.
+			fdB.aws = resourcebuilder.AWSFailureDomain().WithAvailabilityZone("us-east-1a").WithSubnet(machinev1.AWSResourceReference{
+				Type: machinev1.AWSIDReferenceType,
+				ID:   ptrString("subnet-other"),
+			}).Build()
+
+			Expect(fdA.Equal(fdB)).To(BeFalse())
+		})
+	})
+
+	Context("Diff", func() {
+		var current, desired []FailureDomain
+		var added, removed []FailureDomain
+
+		BeforeEach(func() {
+			current = []FailureDomain{
+				failureDomain{platformType: configv1.AWSPlatformType, aws: resourcebuilder.AWSFailureDomain().WithAvailabilityZone("us-east-1a").Build()},
+				failureDomain{platformType: configv1.AWSPlatformType, aws: resourcebuilder.AWSFailureDomain().WithAvailabilityZone("us-east-1b").Build()},
+			}
+
+			desired = []FailureDomain{
+				failureDomain{platformType: configv1.AWSPlatformType, aws: resourcebuilder.AWSFailureDomain().WithAvailabilityZone("us-east-1b").Build()},
+				failureDomain{platformType: configv1.AWSPlatformType, aws: resourcebuilder.AWSFailureDomain().WithAvailabilityZone("us-east-1c").Build()},
+			}
+
+			added, removed = Diff(current, desired)
+		})
+
+		It("returns the failure domains only present in desired as added", func() {
+			Expect(added).To(ConsistOf(
+				HaveField("String()", "AWSFailureDomain{AvailabilityZone:us-east-1c}"),
+			))
+		})
+
+		It("returns the failure domains only present in current as removed", func() {
+			Expect(removed).To(ConsistOf(
+				HaveField("String()", "AWSFailureDomain{AvailabilityZone:us-east-1a}"),
+			))
+		})
+	})
 })
+
+// ptrString returns a pointer to the given string, for use in constructing
+// test fixtures that require a *string.
+func ptrString(s string) *string {
+	return &s
+}
+
+// fakeSubnetResolver is a SubnetResolver that always resolves to the
+// configured subnetID, regardless of the reference it is given.
+type fakeSubnetResolver struct {
+	subnetID string
+}
+
+func (f *fakeSubnetResolver) ResolveSubnet(_ machinev1.AWSResourceReference) (string, error) {
+	return f.subnetID, nil
+}
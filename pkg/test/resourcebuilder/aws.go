@@ -0,0 +1,170 @@
+/*
+Copyright 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcebuilder
+
+import (
+	"encoding/json"
+	"fmt"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// defaultAWSSubnetFilterValue is the subnet tag:Name value used by the
+// default AWS provider spec fixture.
+const defaultAWSSubnetFilterValue = "aws-subnet-12345678"
+
+// AWSFailureDomainsBuilder is used to build a machinev1.FailureDomains with
+// AWS failure domains populated, for use within tests.
+type AWSFailureDomainsBuilder struct {
+	availabilityZones []string
+}
+
+// AWSFailureDomains returns a new AWSFailureDomainsBuilder with sensible
+// defaults, spanning three availability zones. Each failure domain
+// references its subnet by ID, as subenet-<availability zone>.
+func AWSFailureDomains() AWSFailureDomainsBuilder {
+	return AWSFailureDomainsBuilder{
+		availabilityZones: []string{"us-east-1a", "us-east-1b", "us-east-1c"},
+	}
+}
+
+// WithAvailabilityZones sets the availability zones on the
+// AWSFailureDomainsBuilder.
+func (a AWSFailureDomainsBuilder) WithAvailabilityZones(zones []string) AWSFailureDomainsBuilder {
+	a.availabilityZones = zones
+	return a
+}
+
+// BuildFailureDomains builds a machinev1.FailureDomains from the
+// configuration provided.
+func (a AWSFailureDomainsBuilder) BuildFailureDomains() machinev1.FailureDomains {
+	awsFailureDomains := make([]machinev1.AWSFailureDomain, 0, len(a.availabilityZones))
+
+	for _, zone := range a.availabilityZones {
+		subnetID := fmt.Sprintf("subenet-%s", zone)
+
+		awsFailureDomains = append(awsFailureDomains, *AWSFailureDomain().WithAvailabilityZone(zone).WithSubnet(machinev1.AWSResourceReference{
+			Type: machinev1.AWSIDReferenceType,
+			ID:   &subnetID,
+		}).Build())
+	}
+
+	return machinev1.FailureDomains{
+		Platform: configv1.AWSPlatformType,
+		AWS:      &awsFailureDomains,
+	}
+}
+
+// AWSFailureDomainBuilder is used to build a machinev1.AWSFailureDomain for
+// use within tests.
+type AWSFailureDomainBuilder struct {
+	availabilityZone string
+	subnet           *machinev1.AWSResourceReference
+}
+
+// AWSFailureDomain returns a new AWSFailureDomainBuilder with neither an
+// availability zone nor a subnet set.
+func AWSFailureDomain() AWSFailureDomainBuilder {
+	return AWSFailureDomainBuilder{}
+}
+
+// WithAvailabilityZone sets the availability zone on the
+// AWSFailureDomainBuilder.
+func (a AWSFailureDomainBuilder) WithAvailabilityZone(zone string) AWSFailureDomainBuilder {
+	a.availabilityZone = zone
+	return a
+}
+
+// WithSubnet sets the subnet reference on the AWSFailureDomainBuilder.
+func (a AWSFailureDomainBuilder) WithSubnet(subnet machinev1.AWSResourceReference) AWSFailureDomainBuilder {
+	a.subnet = &subnet
+	return a
+}
+
+// Build builds a new machinev1.AWSFailureDomain based on the configuration
+// provided.
+func (a AWSFailureDomainBuilder) Build() *machinev1.AWSFailureDomain {
+	return &machinev1.AWSFailureDomain{
+		AvailabilityZone: a.availabilityZone,
+		Subnet:           a.subnet,
+	}
+}
+
+// AWSProviderSpecBuilder is used to build an AWSMachineProviderSpec for use
+// within tests, wrapped as a RawExtension so it can be used with the
+// Machine builder.
+type AWSProviderSpecBuilder struct {
+	availabilityZone string
+	subnet           machinev1beta1.AWSResourceReference
+}
+
+// AWSProviderSpec returns a new AWSProviderSpecBuilder with the default
+// subnet filter populated.
+func AWSProviderSpec() AWSProviderSpecBuilder {
+	return AWSProviderSpecBuilder{
+		subnet: machinev1beta1.AWSResourceReference{
+			Type: machinev1beta1.AWSFiltersReferenceType,
+			Filters: &[]machinev1beta1.AWSResourceFilter{
+				{
+					Name:   "tag:Name",
+					Values: []string{defaultAWSSubnetFilterValue},
+				},
+			},
+		},
+	}
+}
+
+// WithAvailabilityZone sets the availability zone on the
+// AWSProviderSpecBuilder.
+func (a AWSProviderSpecBuilder) WithAvailabilityZone(zone string) AWSProviderSpecBuilder {
+	a.availabilityZone = zone
+	return a
+}
+
+// WithSubnet sets the subnet reference on the AWSProviderSpecBuilder.
+func (a AWSProviderSpecBuilder) WithSubnet(subnet machinev1beta1.AWSResourceReference) AWSProviderSpecBuilder {
+	a.subnet = subnet
+	return a
+}
+
+// Build builds a new AWSMachineProviderSpec based on the configuration
+// provided.
+func (a AWSProviderSpecBuilder) Build() *machinev1beta1.AWSMachineProviderSpec {
+	return &machinev1beta1.AWSMachineProviderSpec{
+		Placement: machinev1beta1.Placement{
+			AvailabilityZone: a.availabilityZone,
+		},
+		Subnet: a.subnet,
+	}
+}
+
+// BuildRawExtension builds a new AWSMachineProviderSpec, marshalled into a
+// RawExtension, for embedding within a Machine's provider spec.
+func (a AWSProviderSpecBuilder) BuildRawExtension() *runtime.RawExtension {
+	raw, err := json.Marshal(a.Build())
+	if err != nil {
+		// The provider spec types are static and controlled by this
+		// repository, so a marshalling failure here indicates a
+		// programming error in a test fixture.
+		panic(err)
+	}
+
+	return &runtime.RawExtension{Raw: raw}
+}
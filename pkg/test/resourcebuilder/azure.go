@@ -0,0 +1,149 @@
+/*
+Copyright 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcebuilder
+
+import (
+	"encoding/json"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// defaultAzureSubnet is the subnet name used by the default Azure provider
+// spec and failure domain fixtures.
+const defaultAzureSubnet = "azure-subnet-12345678"
+
+// AzureFailureDomainsBuilder is used to build a machinev1.FailureDomains
+// with Azure failure domains populated, for use within tests.
+type AzureFailureDomainsBuilder struct {
+	zones []string
+}
+
+// AzureFailureDomains returns a new AzureFailureDomainsBuilder with
+// sensible defaults, spanning three availability zones.
+func AzureFailureDomains() AzureFailureDomainsBuilder {
+	return AzureFailureDomainsBuilder{
+		zones: []string{"1", "2", "3"},
+	}
+}
+
+// WithZones sets the availability zones on the AzureFailureDomainsBuilder.
+func (a AzureFailureDomainsBuilder) WithZones(zones []string) AzureFailureDomainsBuilder {
+	a.zones = zones
+	return a
+}
+
+// BuildFailureDomains builds a machinev1.FailureDomains from the
+// configuration provided.
+func (a AzureFailureDomainsBuilder) BuildFailureDomains() machinev1.FailureDomains {
+	azureFailureDomains := make([]machinev1.AzureFailureDomain, 0, len(a.zones))
+
+	for _, zone := range a.zones {
+		azureFailureDomains = append(azureFailureDomains, *AzureFailureDomain().WithZone(zone).Build())
+	}
+
+	return machinev1.FailureDomains{
+		Platform: configv1.AzurePlatformType,
+		Azure:    &azureFailureDomains,
+	}
+}
+
+// AzureFailureDomainBuilder is used to build a machinev1.AzureFailureDomain
+// for use within tests.
+type AzureFailureDomainBuilder struct {
+	zone   string
+	subnet string
+}
+
+// AzureFailureDomain returns a new AzureFailureDomainBuilder with the
+// default subnet populated.
+func AzureFailureDomain() AzureFailureDomainBuilder {
+	return AzureFailureDomainBuilder{
+		subnet: defaultAzureSubnet,
+	}
+}
+
+// WithZone sets the availability zone on the AzureFailureDomainBuilder.
+func (a AzureFailureDomainBuilder) WithZone(zone string) AzureFailureDomainBuilder {
+	a.zone = zone
+	return a
+}
+
+// WithSubnet sets the subnet on the AzureFailureDomainBuilder.
+func (a AzureFailureDomainBuilder) WithSubnet(subnet string) AzureFailureDomainBuilder {
+	a.subnet = subnet
+	return a
+}
+
+// Build builds a new machinev1.AzureFailureDomain based on the
+// configuration provided.
+func (a AzureFailureDomainBuilder) Build() *machinev1.AzureFailureDomain {
+	return &machinev1.AzureFailureDomain{
+		Zone:   a.zone,
+		Subnet: a.subnet,
+	}
+}
+
+// AzureProviderSpecBuilder is used to build an AzureMachineProviderSpec for
+// use within tests, wrapped as a RawExtension so it can be used with the
+// Machine builder.
+type AzureProviderSpecBuilder struct {
+	zone   string
+	subnet string
+}
+
+// AzureProviderSpec returns a new AzureProviderSpecBuilder with the
+// default subnet populated.
+func AzureProviderSpec() AzureProviderSpecBuilder {
+	return AzureProviderSpecBuilder{
+		subnet: defaultAzureSubnet,
+	}
+}
+
+// WithZone sets the availability zone on the AzureProviderSpecBuilder.
+func (a AzureProviderSpecBuilder) WithZone(zone string) AzureProviderSpecBuilder {
+	a.zone = zone
+	return a
+}
+
+// WithSubnet sets the subnet on the AzureProviderSpecBuilder.
+func (a AzureProviderSpecBuilder) WithSubnet(subnet string) AzureProviderSpecBuilder {
+	a.subnet = subnet
+	return a
+}
+
+// Build builds a new AzureMachineProviderSpec based on the configuration
+// provided.
+func (a AzureProviderSpecBuilder) Build() *machinev1beta1.AzureMachineProviderSpec {
+	return &machinev1beta1.AzureMachineProviderSpec{
+		Zone:   &a.zone,
+		Subnet: a.subnet,
+	}
+}
+
+// BuildRawExtension builds a new AzureMachineProviderSpec, marshalled into
+// a RawExtension, for embedding within a Machine's provider spec.
+func (a AzureProviderSpecBuilder) BuildRawExtension() *runtime.RawExtension {
+	raw, err := json.Marshal(a.Build())
+	if err != nil {
+		panic(err)
+	}
+
+	return &runtime.RawExtension{Raw: raw}
+}
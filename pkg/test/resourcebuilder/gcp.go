@@ -0,0 +1,153 @@
+/*
+Copyright 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcebuilder
+
+import (
+	"encoding/json"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// defaultGCPSubnetwork is the subnetwork name used by the default GCP
+// provider spec and failure domain fixtures.
+const defaultGCPSubnetwork = "gcp-subnet-12345678"
+
+// GCPFailureDomainsBuilder is used to build a machinev1.FailureDomains with
+// GCP failure domains populated, for use within tests.
+type GCPFailureDomainsBuilder struct {
+	zones []string
+}
+
+// GCPFailureDomains returns a new GCPFailureDomainsBuilder with sensible
+// defaults, spanning three zones.
+func GCPFailureDomains() GCPFailureDomainsBuilder {
+	return GCPFailureDomainsBuilder{
+		zones: []string{"us-central1-a", "us-central1-b", "us-central1-c"},
+	}
+}
+
+// WithZones sets the zones on the GCPFailureDomainsBuilder.
+func (g GCPFailureDomainsBuilder) WithZones(zones []string) GCPFailureDomainsBuilder {
+	g.zones = zones
+	return g
+}
+
+// BuildFailureDomains builds a machinev1.FailureDomains from the
+// configuration provided.
+func (g GCPFailureDomainsBuilder) BuildFailureDomains() machinev1.FailureDomains {
+	gcpFailureDomains := make([]machinev1.GCPFailureDomain, 0, len(g.zones))
+
+	for _, zone := range g.zones {
+		gcpFailureDomains = append(gcpFailureDomains, *GCPFailureDomain().WithZone(zone).Build())
+	}
+
+	return machinev1.FailureDomains{
+		Platform: configv1.GCPPlatformType,
+		GCP:      &gcpFailureDomains,
+	}
+}
+
+// GCPFailureDomainBuilder is used to build a machinev1.GCPFailureDomain for
+// use within tests.
+type GCPFailureDomainBuilder struct {
+	zone   string
+	subnet string
+}
+
+// GCPFailureDomain returns a new GCPFailureDomainBuilder with the default
+// subnetwork populated.
+func GCPFailureDomain() GCPFailureDomainBuilder {
+	return GCPFailureDomainBuilder{
+		subnet: defaultGCPSubnetwork,
+	}
+}
+
+// WithZone sets the zone on the GCPFailureDomainBuilder.
+func (g GCPFailureDomainBuilder) WithZone(zone string) GCPFailureDomainBuilder {
+	g.zone = zone
+	return g
+}
+
+// WithSubnet sets the subnetwork on the GCPFailureDomainBuilder.
+func (g GCPFailureDomainBuilder) WithSubnet(subnet string) GCPFailureDomainBuilder {
+	g.subnet = subnet
+	return g
+}
+
+// Build builds a new machinev1.GCPFailureDomain based on the configuration
+// provided.
+func (g GCPFailureDomainBuilder) Build() *machinev1.GCPFailureDomain {
+	return &machinev1.GCPFailureDomain{
+		Zone:   g.zone,
+		Subnet: g.subnet,
+	}
+}
+
+// GCPProviderSpecBuilder is used to build a GCPMachineProviderSpec for use
+// within tests, wrapped as a RawExtension so it can be used with the
+// Machine builder.
+type GCPProviderSpecBuilder struct {
+	zone   string
+	subnet string
+}
+
+// GCPProviderSpec returns a new GCPProviderSpecBuilder with the default
+// subnetwork populated.
+func GCPProviderSpec() GCPProviderSpecBuilder {
+	return GCPProviderSpecBuilder{
+		subnet: defaultGCPSubnetwork,
+	}
+}
+
+// WithZone sets the zone on the GCPProviderSpecBuilder.
+func (g GCPProviderSpecBuilder) WithZone(zone string) GCPProviderSpecBuilder {
+	g.zone = zone
+	return g
+}
+
+// WithSubnet sets the subnetwork on the GCPProviderSpecBuilder.
+func (g GCPProviderSpecBuilder) WithSubnet(subnet string) GCPProviderSpecBuilder {
+	g.subnet = subnet
+	return g
+}
+
+// Build builds a new GCPMachineProviderSpec based on the configuration
+// provided.
+func (g GCPProviderSpecBuilder) Build() *machinev1beta1.GCPMachineProviderSpec {
+	return &machinev1beta1.GCPMachineProviderSpec{
+		Zone: g.zone,
+		NetworkInterfaces: []*machinev1beta1.GCPNetworkInterface{
+			{
+				Subnetwork: g.subnet,
+			},
+		},
+	}
+}
+
+// BuildRawExtension builds a new GCPMachineProviderSpec, marshalled into a
+// RawExtension, for embedding within a Machine's provider spec.
+func (g GCPProviderSpecBuilder) BuildRawExtension() *runtime.RawExtension {
+	raw, err := json.Marshal(g.Build())
+	if err != nil {
+		panic(err)
+	}
+
+	return &runtime.RawExtension{Raw: raw}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package resourcebuilder provides a set of fluent builders for constructing
+// API objects within unit and integration tests. Each builder is immutable:
+// every With* method returns a new copy of the builder with the requested
+// field set, so that a base builder can be reused and customised for
+// multiple test cases without the customisations leaking into each other.
+package resourcebuilder
+
+import (
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ProviderSpecBuilder is implemented by each of the platform specific
+// provider spec builders so that they can be passed, interchangeably, to
+// the Machine builder.
+type ProviderSpecBuilder interface {
+	BuildRawExtension() *runtime.RawExtension
+}
+
+// MachineBuilder is used to build out a Machine object for use within
+// tests.
+type MachineBuilder struct {
+	name                string
+	namespace           string
+	labels              map[string]string
+	providerSpecBuilder ProviderSpecBuilder
+}
+
+// Machine returns a new MachineBuilder with sensible defaults.
+func Machine() MachineBuilder {
+	return MachineBuilder{
+		name:      "machine",
+		namespace: "openshift-machine-api",
+	}
+}
+
+// WithName sets the name on the MachineBuilder.
+func (m MachineBuilder) WithName(name string) MachineBuilder {
+	m.name = name
+	return m
+}
+
+// WithNamespace sets the namespace on the MachineBuilder.
+func (m MachineBuilder) WithNamespace(namespace string) MachineBuilder {
+	m.namespace = namespace
+	return m
+}
+
+// WithLabels sets the labels on the MachineBuilder.
+func (m MachineBuilder) WithLabels(labels map[string]string) MachineBuilder {
+	m.labels = labels
+	return m
+}
+
+// WithProviderSpecBuilder sets the provider spec builder used to construct
+// the Machine's provider spec on the MachineBuilder.
+func (m MachineBuilder) WithProviderSpecBuilder(builder ProviderSpecBuilder) MachineBuilder {
+	m.providerSpecBuilder = builder
+	return m
+}
+
+// Build builds a new Machine based on the configuration provided.
+func (m MachineBuilder) Build() *machinev1beta1.Machine {
+	machine := &machinev1beta1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      m.name,
+			Namespace: m.namespace,
+			Labels:    m.labels,
+		},
+	}
+
+	if m.providerSpecBuilder != nil {
+		machine.Spec.ProviderSpec.Value = m.providerSpecBuilder.BuildRawExtension()
+	}
+
+	return machine
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcebuilder
+
+import (
+	"encoding/json"
+	"fmt"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// defaultVSphereNetwork is the network name used by the default vSphere
+// provider spec and failure domain fixtures.
+const defaultVSphereNetwork = "vsphere-network"
+
+// VSphereFailureDomainsBuilder is used to build a machinev1.FailureDomains
+// with vSphere failure domains populated, for use within tests.
+type VSphereFailureDomainsBuilder struct {
+	names []string
+}
+
+// VSphereFailureDomains returns a new VSphereFailureDomainsBuilder with
+// sensible defaults, spanning three named failure domains.
+func VSphereFailureDomains() VSphereFailureDomainsBuilder {
+	return VSphereFailureDomainsBuilder{
+		names: []string{"us-east-1a", "us-east-1b", "us-east-1c"},
+	}
+}
+
+// WithNames sets the failure domain names on the
+// VSphereFailureDomainsBuilder.
+func (v VSphereFailureDomainsBuilder) WithNames(names []string) VSphereFailureDomainsBuilder {
+	v.names = names
+	return v
+}
+
+// BuildFailureDomains builds a machinev1.FailureDomains from the
+// configuration provided.
+func (v VSphereFailureDomainsBuilder) BuildFailureDomains() machinev1.FailureDomains {
+	vsphereFailureDomains := make([]machinev1.VSphereFailureDomain, 0, len(v.names))
+
+	for _, name := range v.names {
+		vsphereFailureDomains = append(vsphereFailureDomains, *VSphereFailureDomain().WithName(name).Build())
+	}
+
+	return machinev1.FailureDomains{
+		Platform: configv1.VSpherePlatformType,
+		VSphere:  &vsphereFailureDomains,
+	}
+}
+
+// VSphereFailureDomainBuilder is used to build a
+// machinev1.VSphereFailureDomain for use within tests.
+type VSphereFailureDomainBuilder struct {
+	name           string
+	region         string
+	zone           string
+	server         string
+	datacenter     string
+	computeCluster string
+	datastore      string
+	networks       []string
+	resourcePool   string
+	folder         string
+}
+
+// VSphereFailureDomain returns a new VSphereFailureDomainBuilder with
+// sensible defaults for its topology. Server, ResourcePool and Folder are
+// left unset so that Build derives them from the failure domain name: this
+// keeps distinctly named fixtures distinguishable by workspace alone,
+// matching how NewFailureDomainsFromMachines actually recovers a Machine's
+// failure domain (by workspace, never by name).
+func VSphereFailureDomain() VSphereFailureDomainBuilder {
+	return VSphereFailureDomainBuilder{
+		region:         "region-1",
+		zone:           "zone-1",
+		datacenter:     "datacenter",
+		computeCluster: "/datacenter/host/cluster",
+		datastore:      "/datacenter/datastore/datastore",
+		networks:       []string{defaultVSphereNetwork},
+	}
+}
+
+// WithName sets the failure domain name on the VSphereFailureDomainBuilder.
+func (v VSphereFailureDomainBuilder) WithName(name string) VSphereFailureDomainBuilder {
+	v.name = name
+	return v
+}
+
+// WithServer sets the vCenter server on the VSphereFailureDomainBuilder.
+func (v VSphereFailureDomainBuilder) WithServer(server string) VSphereFailureDomainBuilder {
+	v.server = server
+	return v
+}
+
+// WithRegion sets the region on the VSphereFailureDomainBuilder.
+func (v VSphereFailureDomainBuilder) WithRegion(region string) VSphereFailureDomainBuilder {
+	v.region = region
+	return v
+}
+
+// WithZone sets the zone on the VSphereFailureDomainBuilder.
+func (v VSphereFailureDomainBuilder) WithZone(zone string) VSphereFailureDomainBuilder {
+	v.zone = zone
+	return v
+}
+
+// WithNetworks sets the topology networks on the
+// VSphereFailureDomainBuilder.
+func (v VSphereFailureDomainBuilder) WithNetworks(networks []string) VSphereFailureDomainBuilder {
+	v.networks = networks
+	return v
+}
+
+// Build builds a new machinev1.VSphereFailureDomain based on the
+// configuration provided. When Server, ResourcePool or Folder have not
+// been set explicitly, they are derived from the failure domain name so
+// that each named fixture has a distinct workspace, the same way real
+// per-zone vSphere failure domains do.
+func (v VSphereFailureDomainBuilder) Build() *machinev1.VSphereFailureDomain {
+	server := v.server
+	if server == "" {
+		server = fmt.Sprintf("%s.vcenter.example.com", v.name)
+	}
+
+	resourcePool := v.resourcePool
+	if resourcePool == "" {
+		resourcePool = fmt.Sprintf("/%s/host/cluster/Resources/%s", v.datacenter, v.name)
+	}
+
+	folder := v.folder
+	if folder == "" {
+		folder = fmt.Sprintf("/%s/vm/%s", v.datacenter, v.name)
+	}
+
+	return &machinev1.VSphereFailureDomain{
+		Name:   v.name,
+		Region: v.region,
+		Zone:   v.zone,
+		Server: server,
+		Topology: machinev1.VSphereFailureDomainTopology{
+			Datacenter:     v.datacenter,
+			ComputeCluster: v.computeCluster,
+			Datastore:      v.datastore,
+			Networks:       v.networks,
+			ResourcePool:   resourcePool,
+			Folder:         folder,
+		},
+	}
+}
+
+// VSphereProviderSpecBuilder is used to build a VSphereMachineProviderSpec
+// for use within tests, wrapped as a RawExtension so it can be used with
+// the Machine builder.
+type VSphereProviderSpecBuilder struct {
+	failureDomain VSphereFailureDomainBuilder
+}
+
+// VSphereProviderSpec returns a new VSphereProviderSpecBuilder whose
+// workspace and network match the default VSphereFailureDomainBuilder
+// topology.
+func VSphereProviderSpec() VSphereProviderSpecBuilder {
+	return VSphereProviderSpecBuilder{
+		failureDomain: VSphereFailureDomain(),
+	}
+}
+
+// WithFailureDomainTopology configures the provider spec's workspace and
+// network devices to match the topology of the given failure domain
+// builder, so that NewFailureDomainsFromMachines can reverse map the
+// Machine back to it.
+func (v VSphereProviderSpecBuilder) WithFailureDomainTopology(fd VSphereFailureDomainBuilder) VSphereProviderSpecBuilder {
+	v.failureDomain = fd
+	return v
+}
+
+// Build builds a new VSphereMachineProviderSpec based on the configuration
+// provided.
+func (v VSphereProviderSpecBuilder) Build() *machinev1beta1.VSphereMachineProviderSpec {
+	fd := v.failureDomain.Build()
+	topology := fd.Topology
+
+	devices := make([]machinev1beta1.NetworkDeviceSpec, 0, len(topology.Networks))
+	for _, network := range topology.Networks {
+		devices = append(devices, machinev1beta1.NetworkDeviceSpec{NetworkName: network})
+	}
+
+	return &machinev1beta1.VSphereMachineProviderSpec{
+		Workspace: &machinev1beta1.Workspace{
+			Server:       fd.Server,
+			Datacenter:   topology.Datacenter,
+			Datastore:    topology.Datastore,
+			ResourcePool: topology.ResourcePool,
+			Folder:       topology.Folder,
+		},
+		Network: machinev1beta1.NetworkSpec{
+			Devices: devices,
+		},
+	}
+}
+
+// BuildRawExtension builds a new VSphereMachineProviderSpec, marshalled
+// into a RawExtension, for embedding within a Machine's provider spec.
+func (v VSphereProviderSpecBuilder) BuildRawExtension() *runtime.RawExtension {
+	raw, err := json.Marshal(v.Build())
+	if err != nil {
+		panic(fmt.Errorf("error marshalling vSphere provider spec: %w", err))
+	}
+
+	return &runtime.RawExtension{Raw: raw}
+}